@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheEntry is one cached fetch, keyed by provider, location and unit combination.
+type cacheEntry struct {
+	Cur      Current   `json:"current"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// Cache is a small on-disk JSON cache for Current results, keyed by
+// (provider, lat, lon, units) so that interactive runs within Open-Meteo's refresh
+// window don't have to hit the network again.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]cacheEntry
+
+	hits   int
+	misses int
+}
+
+// loadCache reads an existing cache file from disk, if present, and
+// returns a Cache ready for Get/Set. A missing or corrupt file just
+// starts with an empty cache rather than failing the run.
+func loadCache(path string, ttl time.Duration) *Cache {
+	c := &Cache{
+		path:    path,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+	if path == "" {
+		return c
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var entries map[string]cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return c
+	}
+	c.entries = entries
+	return c
+}
+
+// cacheKeyFor builds the cache key for a city under the given provider
+// and units. The provider must be part of the key: two providers can
+// return materially different values for the same city/units, so a run
+// with -provider=wttr must not reuse a cache entry warmed by
+// -provider=openmeteo.
+func cacheKeyFor(provider string, city City, fahrenheit, mph bool) string {
+	return fmt.Sprintf("%s,%.4f,%.4f,f=%v,mph=%v", provider, city.Lat, city.Lon, fahrenheit, mph)
+}
+
+// Get returns the cached Current for key if it exists and hasn't expired.
+func (c *Cache) Get(key string) (Current, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.StoredAt) > c.ttl {
+		c.misses++
+		return Current{}, false
+	}
+	c.hits++
+	return entry.Cur, true
+}
+
+// Set stores cur under key, stamped with the current time.
+func (c *Cache) Set(key string, cur Current) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{Cur: cur, StoredAt: time.Now()}
+}
+
+// Stats returns the hit/miss counters accumulated since the cache was loaded.
+func (c *Cache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// Save writes the cache back to disk atomically (write to a temp file in
+// the same directory, then rename) so a crash mid-write can't corrupt it.
+func (c *Cache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	tmp, err := os.CreateTemp(dir, ".cache-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
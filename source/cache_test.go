@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheGetSetHit(t *testing.T) {
+	c := loadCache("", time.Minute)
+	key := cacheKeyFor("openmeteo", City{State: "Texas", Name: "Austin"}, true, true)
+
+	if _, ok := c.Get(key); ok {
+		t.Fatalf("expected miss on empty cache")
+	}
+
+	want := Current{Temperature: 99.5}
+	c.Set(key, want)
+
+	got, ok := c.Get(key)
+	if !ok {
+		t.Fatalf("expected hit after Set")
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	hits, misses := c.Stats()
+	if hits != 1 || misses != 1 {
+		t.Fatalf("got hits=%d misses=%d, want hits=1 misses=1", hits, misses)
+	}
+}
+
+func TestCacheExpiresAfterTTL(t *testing.T) {
+	c := loadCache("", time.Millisecond)
+	key := cacheKeyFor("openmeteo", City{State: "Texas", Name: "Austin"}, true, true)
+	c.Set(key, Current{Temperature: 70})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get(key); ok {
+		t.Fatalf("expected entry to expire after TTL")
+	}
+}
+
+func TestCacheKeyForIncludesProvider(t *testing.T) {
+	city := City{State: "Texas", Name: "Austin", Lat: 30.27, Lon: -97.74}
+	a := cacheKeyFor("openmeteo", city, true, true)
+	b := cacheKeyFor("wttr", city, true, true)
+	if a == b {
+		t.Fatalf("expected different cache keys for different providers, both got %q", a)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// nextWarmTime returns the next moment at which the prefetcher should run:
+// one minute before the next quarter hour (Open-Meteo refreshes roughly
+// every 15 minutes, so warming just ahead of that keeps the cache hot for
+// interactive runs).
+func nextWarmTime(now time.Time, lead time.Duration) time.Time {
+	floor := now.Truncate(15 * time.Minute)
+	next := floor.Add(15 * time.Minute)
+	warm := next.Add(-lead)
+	if !warm.After(now) {
+		warm = warm.Add(15 * time.Minute)
+	}
+	return warm
+}
+
+// runDaemon blocks, warming the cache for every capital shortly before
+// each quarter hour, until ctx is cancelled.
+func runDaemon(ctx context.Context, provider Provider, providerName string, capitals []City, cache *Cache, fahrenheit, mph bool, lead time.Duration) {
+	for {
+		warm := nextWarmTime(time.Now(), lead)
+		timer := time.NewTimer(time.Until(warm))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			prefetchAll(ctx, provider, providerName, capitals, cache, fahrenheit, mph)
+		}
+	}
+}
+
+// prefetchAll fetches every capital concurrently and stores the results in
+// cache, ignoring individual errors (a failed prefetch just leaves that
+// city to be fetched live on the next interactive run).
+func prefetchAll(ctx context.Context, provider Provider, providerName string, capitals []City, cache *Cache, fahrenheit, mph bool) {
+	var wg sync.WaitGroup
+	for _, city := range capitals {
+		wg.Add(1)
+		go func(city City) {
+			defer wg.Done()
+			cur, err := provider.Fetch(ctx, city)
+			if err != nil {
+				slog.Error("prefetch failed", "state", city.State, "capital", city.Name, "error", err)
+				return
+			}
+			cache.Set(cacheKeyFor(providerName, city, fahrenheit, mph), cur)
+		}(city)
+	}
+	wg.Wait()
+	if err := cache.Save(); err != nil {
+		slog.Error("prefetch: saving cache", "error", err)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextWarmTime(t *testing.T) {
+	lead := time.Minute
+
+	tests := []struct {
+		name string
+		now  string
+		want string
+	}{
+		{"well before the quarter hour", "2026-07-29T10:00:00Z", "2026-07-29T10:14:00Z"},
+		{"just before the lead window", "2026-07-29T10:13:59Z", "2026-07-29T10:14:00Z"},
+		{"inside the lead window", "2026-07-29T10:14:30Z", "2026-07-29T10:29:00Z"},
+		{"exactly on the warm time", "2026-07-29T10:14:00Z", "2026-07-29T10:29:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, tt.now)
+			if err != nil {
+				t.Fatalf("parsing now: %v", err)
+			}
+			want, err := time.Parse(time.RFC3339, tt.want)
+			if err != nil {
+				t.Fatalf("parsing want: %v", err)
+			}
+
+			got := nextWarmTime(now, lead)
+			if !got.Equal(want) {
+				t.Fatalf("nextWarmTime(%s) = %s, want %s", tt.now, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HourlyPoint is one hourly forecast sample.
+type HourlyPoint struct {
+	Time              string  `json:"time"`
+	Temperature       float64 `json:"temperature"`
+	PrecipProbability float64 `json:"precip_probability"`
+}
+
+// DailyPoint is one daily forecast summary.
+type DailyPoint struct {
+	Date        string  `json:"date"`
+	TempMax     float64 `json:"temp_max"`
+	TempMin     float64 `json:"temp_min"`
+	WeatherCode int     `json:"weather_code"`
+	Sunrise     string  `json:"sunrise"`
+	Sunset      string  `json:"sunset"`
+}
+
+// Forecast holds the hourly and daily series for a city, plus the
+// timezone Open-Meteo resolved for it so times can be shown local.
+type Forecast struct {
+	City     City          `json:"city"`
+	Timezone string        `json:"timezone"`
+	Hourly   []HourlyPoint `json:"hourly,omitempty"`
+	Daily    []DailyPoint  `json:"daily,omitempty"`
+}
+
+// omForecastResponse covers the hourly/daily fields this tool requests
+// from Open-Meteo's forecast endpoint.
+type omForecastResponse struct {
+	Timezone string `json:"timezone"`
+	Hourly   struct {
+		Time                     []string  `json:"time"`
+		Temperature2m            []float64 `json:"temperature_2m"`
+		PrecipitationProbability []float64 `json:"precipitation_probability"`
+	} `json:"hourly"`
+	Daily struct {
+		Time             []string  `json:"time"`
+		Sunrise          []string  `json:"sunrise"`
+		Sunset           []string  `json:"sunset"`
+		WeatherCode      []int     `json:"weather_code"`
+		Temperature2mMax []float64 `json:"temperature_2m_max"`
+		Temperature2mMin []float64 `json:"temperature_2m_min"`
+	} `json:"daily"`
+}
+
+// fetchForecast requests hourly and/or daily series for city, depending
+// on mode ("hourly", "daily", or "forecast" for both).
+func fetchForecast(ctx context.Context, client *http.Client, city City, fahrenheit, mph bool, mode string, days int) (Forecast, error) {
+	unitsTemp := "celsius"
+	if fahrenheit {
+		unitsTemp = "fahrenheit"
+	}
+	unitsWind := "kmh"
+	if mph {
+		unitsWind = "mph"
+	}
+
+	var params string
+	switch mode {
+	case "hourly":
+		params = "hourly=temperature_2m,precipitation_probability"
+	case "daily":
+		params = "daily=temperature_2m_max,temperature_2m_min,sunrise,sunset,weather_code"
+	default: // "forecast"
+		params = "hourly=temperature_2m,precipitation_probability&daily=temperature_2m_max,temperature_2m_min,sunrise,sunset,weather_code"
+	}
+
+	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&%s&temperature_unit=%s&wind_speed_unit=%s&timezone=auto&forecast_days=%d",
+		city.Lat, city.Lon, params, unitsTemp, unitsWind, days)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Forecast{}, err
+	}
+	req.Header.Set("User-Agent", "go-capitals-weather/1.0 (+https://example.local)")
+
+	resp, err := doWithRetry(ctx, client, city.State, req)
+	if err != nil {
+		return Forecast{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Forecast{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var om omForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&om); err != nil {
+		return Forecast{}, err
+	}
+
+	f := Forecast{City: city, Timezone: om.Timezone}
+	for i, t := range om.Hourly.Time {
+		f.Hourly = append(f.Hourly, HourlyPoint{
+			Time:              t,
+			Temperature:       valueAt(om.Hourly.Temperature2m, i),
+			PrecipProbability: valueAt(om.Hourly.PrecipitationProbability, i),
+		})
+	}
+	for i, d := range om.Daily.Time {
+		f.Daily = append(f.Daily, DailyPoint{
+			Date:        d,
+			TempMax:     valueAt(om.Daily.Temperature2mMax, i),
+			TempMin:     valueAt(om.Daily.Temperature2mMin, i),
+			WeatherCode: intAt(om.Daily.WeatherCode, i),
+			Sunrise:     localTime(valueAt2(om.Daily.Sunrise, i), om.Timezone),
+			Sunset:      localTime(valueAt2(om.Daily.Sunset, i), om.Timezone),
+		})
+	}
+	return f, nil
+}
+
+// fetchAllForecasts fetches forecasts for every city concurrently,
+// bounded by concurrency, and returns them sorted by state. Per-city
+// errors are logged and simply omit that city from the result.
+func fetchAllForecasts(ctx context.Context, client *http.Client, capitals []City, concurrency int, fahrenheit, mph bool, mode string, days int) []Forecast {
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	results := make(chan Forecast, len(capitals))
+
+	for _, city := range capitals {
+		wg.Add(1)
+		go func(city City) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			f, err := fetchForecast(ctx, client, city, fahrenheit, mph, mode, days)
+			if err != nil {
+				slog.Error("forecast failed", "state", city.State, "capital", city.Name, "error", err)
+				return
+			}
+			results <- f
+		}(city)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var out []Forecast
+	for f := range results {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].City.State < out[j].City.State })
+	return out
+}
+
+func valueAt(vals []float64, i int) float64 {
+	if i < 0 || i >= len(vals) {
+		return 0
+	}
+	return vals[i]
+}
+
+func intAt(vals []int, i int) int {
+	if i < 0 || i >= len(vals) {
+		return 0
+	}
+	return vals[i]
+}
+
+func valueAt2(vals []string, i int) string {
+	if i < 0 || i >= len(vals) {
+		return ""
+	}
+	return vals[i]
+}
+
+// localTime formats an Open-Meteo local timestamp ("2006-01-02T15:04",
+// already adjusted for tz) as a short clock time with the zone
+// abbreviation, falling back to the raw timestamp if tz can't be loaded.
+func localTime(ts, tz string) string {
+	if ts == "" {
+		return ts
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return ts
+	}
+	parsed, err := time.ParseInLocation("2006-01-02T15:04", ts, loc)
+	if err != nil {
+		return ts
+	}
+	return parsed.Format("15:04 MST")
+}
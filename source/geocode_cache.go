@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// GeocodeCache persists resolved "-city" queries to their City on disk,
+// so repeated runs skip the geocoding round-trip entirely (unlike the
+// weather Cache, entries never expire: a city's coordinates don't change).
+type GeocodeCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]City
+}
+
+// loadGeocodeCache reads an existing geocode cache file, if present.
+func loadGeocodeCache(path string) *GeocodeCache {
+	c := &GeocodeCache{path: path, entries: make(map[string]City)}
+	if path == "" {
+		return c
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var entries map[string]City
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return c
+	}
+	c.entries = entries
+	return c
+}
+
+func geocodeCacheKey(raw string) string {
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+// Get returns the cached City for a "-city" query, if resolved before.
+func (c *GeocodeCache) Get(raw string) (City, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	city, ok := c.entries[geocodeCacheKey(raw)]
+	return city, ok
+}
+
+// Set records the resolved City for a "-city" query and persists it to
+// disk. A write failure is non-fatal: it just costs a round-trip next run.
+func (c *GeocodeCache) Set(raw string, city City) error {
+	c.mu.Lock()
+	c.entries[geocodeCacheKey(raw)] = city
+	c.mu.Unlock()
+	return c.save()
+}
+
+func (c *GeocodeCache) save() error {
+	if c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	tmp, err := os.CreateTemp(dir, ".geocode-cache-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -6,12 +6,15 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
 	"runtime"
 	"sort"
 	"strings"
 	"sync"
 	"time"
+	_ "time/tzdata"
 )
 
 type City struct {
@@ -49,18 +52,86 @@ func main() {
 	timeout := flag.Duration("timeout", 20*time.Second, "overall timeout for the run")
 	fahrenheit := flag.Bool("f", true, "use Fahrenheit (otherwise Celsius)")
 	mph := flag.Bool("mph", true, "use mph for wind speed")
+	cachePath := flag.String("cache", "capitals-weather-cache.json", "path to the on-disk result cache (empty disables caching)")
+	cacheTTL := flag.Duration("cache-ttl", 15*time.Minute, "how long a cached result stays fresh")
+	daemon := flag.Bool("daemon", false, "run as a background daemon that keeps the cache warm instead of printing once and exiting")
+	prefetchLead := flag.Duration("prefetch-lead", time.Minute, "in -daemon mode, how long before each quarter hour to warm the cache")
+	providerName := flag.String("provider", "openmeteo", "weather backend: openmeteo, openweathermap, wttr, or auto")
+	apiKey := flag.String("api-key", "", "API key for providers that require one (e.g. openweathermap)")
+	lang := flag.String("lang", "", "language code for providers that support it (e.g. openweathermap)")
+	mode := flag.String("mode", "current", "current|hourly|daily|forecast")
+	days := flag.Int("days", 3, "number of days to request in hourly/daily/forecast mode")
+	format := flag.String("format", "table", "table|json|csv|ndjson")
+	serve := flag.String("serve", "", "if set, run a long-lived HTTP server on this address (e.g. :8080) instead of a one-shot run")
+	scrapeInterval := flag.Duration("scrape-interval", 15*time.Minute, "in -serve mode, how often to refresh every capital")
+	var cityFlags stringSliceFlag
+	flag.Var(&cityFlags, "city", "a \"City, Region\" to fetch instead of the default 50 capitals (repeatable)")
+	citiesFile := flag.String("cities-file", "", "newline-separated file of \"City, Region\" queries, used instead of the default 50 capitals")
+	geocodeCachePath := flag.String("geocode-cache", "geocode-cache.json", "path to the on-disk geocode cache (empty disables caching)")
+	rate := flag.Float64("rate", 10, "max requests/second across all goroutines and providers (0 disables rate limiting)")
+	logLevel := flag.String("log-level", "warn", "structured log verbosity: debug|info|warn|error")
 	flag.Parse()
 
 	if *singleThread {
 		runtime.GOMAXPROCS(1)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
-	defer cancel()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: parseLogLevel(*logLevel)})))
+	sharedLimiter = NewRateLimiter(*rate)
 
 	client := &http.Client{Timeout: 8 * time.Second}
 
 	capitals := usStateCapitals()
+	if len(cityFlags) > 0 || *citiesFile != "" {
+		queries := []string(cityFlags)
+		if *citiesFile != "" {
+			fileQueries, err := readCitiesFile(*citiesFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "reading -cities-file: %v\n", err)
+				os.Exit(1)
+			}
+			queries = append(queries, fileQueries...)
+		}
+
+		geocodeCtx, geocodeCancel := context.WithTimeout(context.Background(), *timeout)
+		capitals = resolvePlaces(geocodeCtx, client, loadGeocodeCache(*geocodeCachePath), queries)
+		geocodeCancel()
+	}
+
+	cache := loadCache(*cachePath, *cacheTTL)
+
+	provider, err := newProvider(*providerName, client, *apiKey, *lang, *fahrenheit, *mph)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if *daemon {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		runDaemon(ctx, provider, *providerName, capitals, cache, *fahrenheit, *mph, *prefetchLead)
+		return
+	}
+
+	if *serve != "" {
+		if err := runServer(context.Background(), *serve, capitals, provider, *providerName, *concurrency, *fahrenheit, *mph, *scrapeInterval); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if *mode != "current" {
+		out := fetchAllForecasts(ctx, client, capitals, *concurrency, *fahrenheit, *mph, *mode, *days)
+		if err := renderForecasts(out, *mode, *format); err != nil {
+			fmt.Fprintf(os.Stderr, "rendering forecasts: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// semaphore to limit in-flight requests
 	sem := make(chan struct{}, *concurrency)
@@ -74,7 +145,16 @@ func main() {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			cur, err := fetchCurrent(ctx, client, city, *fahrenheit, *mph)
+			key := cacheKeyFor(*providerName, city, *fahrenheit, *mph)
+			if cur, ok := cache.Get(key); ok {
+				results <- Result{City: city, Cur: cur}
+				return
+			}
+
+			cur, err := provider.Fetch(ctx, city)
+			if err == nil {
+				cache.Set(key, cur)
+			}
 			results <- Result{City: city, Cur: cur, Err: err}
 		}(city)
 	}
@@ -92,20 +172,31 @@ func main() {
 	// sort results by state
 	sort.Slice(out, func(i, j int) bool { return out[i].City.State < out[j].City.State })
 
-	// print a simple table
-	fmt.Printf("%s\n", strings.Repeat("-", 86))
-	fmt.Printf("%-15s | %-18s | %9s | %7s | %3s | %s\n", "STATE", "CAPITAL", "TEMP", "WIND", "DIR", "AT")
-	fmt.Printf("%s\n", strings.Repeat("-", 86))
-	for _, r := range out {
-		if r.Err != nil {
-			fmt.Printf("%-15s | %-18s | %9s | %7s | %3s | %v\n", r.City.State, r.City.Name, "ERR", "-", "-", r.Err)
-			continue
-		}
-		dir := windDir(r.Cur.WindDirection)
-		fmt.Printf("%-15s | %-18s | %6.1f° | %5.1f | %3s | %s\n",
-			r.City.State, r.City.Name, r.Cur.Temperature, r.Cur.WindSpeed, dir, r.Cur.Time)
+	if err := renderCurrent(out, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "rendering results: %v\n", err)
+		os.Exit(1)
+	}
+
+	hits, misses := cache.Stats()
+	fmt.Printf("cache: %d hit(s), %d miss(es)\n", hits, misses)
+	if err := cache.Save(); err != nil {
+		slog.Warn("saving cache", "error", err)
+	}
+}
+
+// parseLogLevel maps a -log-level flag value to a slog.Level, defaulting
+// to Warn for anything unrecognized.
+func parseLogLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
 	}
-	fmt.Printf("%s\n", strings.Repeat("-", 86))
 }
 
 func fetchCurrent(ctx context.Context, client *http.Client, city City, fahrenheit, mph bool) (Current, error) {
@@ -128,7 +219,7 @@ func fetchCurrent(ctx context.Context, client *http.Client, city City, fahrenhei
 	}
 	req.Header.Set("User-Agent", "go-capitals-weather/1.0 (+https://example.local)")
 
-	resp, err := client.Do(req)
+	resp, err := doWithRetry(ctx, client, city.State, req)
 	if err != nil {
 		return Current{}, err
 	}
@@ -154,59 +245,3 @@ func windDir(deg float64) string {
 	idx := int((deg/22.5)+0.5) % 16
 	return dirs[idx]
 }
-
-func usStateCapitals() []City {
-	// Lat/Lon roughly for downtown/statehouse; good enough for demo purposes.
-	return []City{
-		{"Alabama", "Montgomery", 32.377716, -86.300568},
-		{"Alaska", "Juneau", 58.301598, -134.420212},
-		{"Arizona", "Phoenix", 33.448143, -112.096962},
-		{"Arkansas", "Little Rock", 34.746613, -92.288986},
-		{"California", "Sacramento", 38.576668, -121.493629},
-		{"Colorado", "Denver", 39.739227, -104.984856},
-		{"Connecticut", "Hartford", 41.764046, -72.682198},
-		{"Delaware", "Dover", 39.157307, -75.519722},
-		{"Florida", "Tallahassee", 30.438118, -84.281296},
-		{"Georgia", "Atlanta", 33.748997, -84.387985},
-		{"Hawaii", "Honolulu", 21.304850, -157.857758},
-		{"Idaho", "Boise", 43.615021, -116.202316},
-		{"Illinois", "Springfield", 39.798363, -89.654961},
-		{"Indiana", "Indianapolis", 39.768402, -86.158066},
-		{"Iowa", "Des Moines", 41.591087, -93.603729},
-		{"Kansas", "Topeka", 39.047345, -95.675157},
-		{"Kentucky", "Frankfort", 38.186722, -84.875374},
-		{"Louisiana", "Baton Rouge", 30.457069, -91.187393},
-		{"Maine", "Augusta", 44.307167, -69.781693},
-		{"Maryland", "Annapolis", 38.978764, -76.490936},
-		{"Massachusetts", "Boston", 42.358162, -71.063698},
-		{"Michigan", "Lansing", 42.733635, -84.555328},
-		{"Minnesota", "Saint Paul", 44.955097, -93.102211},
-		{"Mississippi", "Jackson", 32.303848, -90.182106},
-		{"Missouri", "Jefferson City", 38.579201, -92.172935},
-		{"Montana", "Helena", 46.585709, -112.018417},
-		{"Nebraska", "Lincoln", 40.808075, -96.699654},
-		{"Nevada", "Carson City", 39.163914, -119.766121},
-		{"New Hampshire", "Concord", 43.206898, -71.537994},
-		{"New Jersey", "Trenton", 40.220596, -74.769913},
-		{"New Mexico", "Santa Fe", 35.682240, -105.939728},
-		{"New York", "Albany", 42.652843, -73.757874},
-		{"North Carolina", "Raleigh", 35.780430, -78.639099},
-		{"North Dakota", "Bismarck", 46.820850, -100.783318},
-		{"Ohio", "Columbus", 39.961346, -82.999069},
-		{"Oklahoma", "Oklahoma City", 35.492207, -97.503342},
-		{"Oregon", "Salem", 44.938461, -123.030403},
-		{"Pennsylvania", "Harrisburg", 40.264378, -76.883598},
-		{"Rhode Island", "Providence", 41.830914, -71.414963},
-		{"South Carolina", "Columbia", 34.000343, -81.033211},
-		{"South Dakota", "Pierre", 44.367031, -100.346405},
-		{"Tennessee", "Nashville", 36.165810, -86.784241},
-		{"Texas", "Austin", 30.274670, -97.740349},
-		{"Utah", "Salt Lake City", 40.777477, -111.888237},
-		{"Vermont", "Montpelier", 44.262436, -72.580536},
-		{"Virginia", "Richmond", 37.538857, -77.433640},
-		{"Washington", "Olympia", 47.035805, -122.905014},
-		{"West Virginia", "Charleston", 38.336246, -81.612328},
-		{"Wisconsin", "Madison", 43.074684, -89.384445},
-		{"Wyoming", "Cheyenne", 41.140259, -104.820236},
-	}
-}
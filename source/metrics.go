@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// metricKey identifies a weather gauge series by its Prometheus labels.
+type metricKey struct {
+	State   string
+	Capital string
+	Unit    string
+}
+
+// errorKey identifies a fetch-error counter series.
+type errorKey struct {
+	State    string
+	Provider string
+}
+
+// durationBuckets are the histogram bucket upper bounds, in seconds, for
+// capitals_weather_fetch_duration_seconds.
+var durationBuckets = []float64{0.1, 0.25, 0.5, 1, 2, 4, 8, 16}
+
+// histogram is a minimal fixed-bucket Prometheus-style histogram.
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sum += v
+	h.count++
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+}
+
+func (h *histogram) writeProm(w io.Writer, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, upper := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, upper, h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// metricsRegistry accumulates the gauges and counters exposed on
+// /metrics, mirroring how the Telegraf openweathermap plugin tags
+// temperature/wind measurements by state and capital.
+type metricsRegistry struct {
+	mu            sync.Mutex
+	temperature   map[metricKey]float64
+	windSpeed     map[metricKey]float64
+	windDirection map[metricKey]float64
+	fetchErrors   map[errorKey]uint64
+	fetchDuration *histogram
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		temperature:   make(map[metricKey]float64),
+		windSpeed:     make(map[metricKey]float64),
+		windDirection: make(map[metricKey]float64),
+		fetchErrors:   make(map[errorKey]uint64),
+		fetchDuration: newHistogram(durationBuckets),
+	}
+}
+
+// observeFetch records the outcome of one scrape attempt for a city.
+func (m *metricsRegistry) observeFetch(city City, provider string, cur Current, fahrenheit, mph bool, err error, duration time.Duration) {
+	m.fetchDuration.observe(duration.Seconds())
+
+	if err != nil {
+		m.mu.Lock()
+		m.fetchErrors[errorKey{State: city.State, Provider: provider}]++
+		m.mu.Unlock()
+		return
+	}
+
+	tempUnit := "celsius"
+	if fahrenheit {
+		tempUnit = "fahrenheit"
+	}
+	windUnit := "kmh"
+	if mph {
+		windUnit = "mph"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.temperature[metricKey{State: city.State, Capital: city.Name, Unit: tempUnit}] = cur.Temperature
+	m.windSpeed[metricKey{State: city.State, Capital: city.Name, Unit: windUnit}] = cur.WindSpeed
+	m.windDirection[metricKey{State: city.State, Capital: city.Name, Unit: "degrees"}] = cur.WindDirection
+}
+
+// writePrometheus renders the registry in the Prometheus text exposition
+// format.
+func (m *metricsRegistry) writePrometheus(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP capitals_weather_temperature Current temperature at a state capital.")
+	fmt.Fprintln(w, "# TYPE capitals_weather_temperature gauge")
+	for _, k := range sortedMetricKeys(m.temperature) {
+		fmt.Fprintf(w, "capitals_weather_temperature{state=%q,capital=%q,unit=%q} %g\n", k.State, k.Capital, k.Unit, m.temperature[k])
+	}
+
+	fmt.Fprintln(w, "# HELP capitals_weather_wind_speed Current wind speed at a state capital.")
+	fmt.Fprintln(w, "# TYPE capitals_weather_wind_speed gauge")
+	for _, k := range sortedMetricKeys(m.windSpeed) {
+		fmt.Fprintf(w, "capitals_weather_wind_speed{state=%q,capital=%q,unit=%q} %g\n", k.State, k.Capital, k.Unit, m.windSpeed[k])
+	}
+
+	fmt.Fprintln(w, "# HELP capitals_weather_wind_direction_degrees Current wind direction at a state capital.")
+	fmt.Fprintln(w, "# TYPE capitals_weather_wind_direction_degrees gauge")
+	for _, k := range sortedMetricKeys(m.windDirection) {
+		fmt.Fprintf(w, "capitals_weather_wind_direction_degrees{state=%q,capital=%q,unit=%q} %g\n", k.State, k.Capital, k.Unit, m.windDirection[k])
+	}
+
+	fmt.Fprintln(w, "# HELP capitals_weather_fetch_errors_total Total fetch errors by state and provider.")
+	fmt.Fprintln(w, "# TYPE capitals_weather_fetch_errors_total counter")
+	for _, k := range sortedErrorKeys(m.fetchErrors) {
+		fmt.Fprintf(w, "capitals_weather_fetch_errors_total{state=%q,provider=%q} %d\n", k.State, k.Provider, m.fetchErrors[k])
+	}
+
+	fmt.Fprintln(w, "# HELP capitals_weather_fetch_duration_seconds Time spent fetching weather for one city.")
+	fmt.Fprintln(w, "# TYPE capitals_weather_fetch_duration_seconds histogram")
+	m.fetchDuration.writeProm(w, "capitals_weather_fetch_duration_seconds")
+}
+
+func sortedMetricKeys(m map[metricKey]float64) []metricKey {
+	keys := make([]metricKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].State != keys[j].State {
+			return keys[i].State < keys[j].State
+		}
+		return keys[i].Capital < keys[j].Capital
+	})
+	return keys
+}
+
+func sortedErrorKeys(m map[errorKey]uint64) []errorKey {
+	keys := make([]errorKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].State != keys[j].State {
+			return keys[i].State < keys[j].State
+		}
+		return keys[i].Provider < keys[j].Provider
+	})
+	return keys
+}
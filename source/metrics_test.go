@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHistogramBucketCounts(t *testing.T) {
+	h := newHistogram([]float64{0.25, 0.5, 1})
+	for _, v := range []float64{0.1, 0.2, 0.4, 0.8, 2} {
+		h.observe(v)
+	}
+
+	var buf bytes.Buffer
+	h.writeProm(&buf, "fetch_duration_seconds")
+	out := buf.String()
+
+	// 0.1 and 0.2 fall in the <=0.25 bucket; 0.4 also falls in the
+	// cumulative <=0.5 bucket; 0.8 in <=1; and 2 only in +Inf.
+	if !strings.Contains(out, `fetch_duration_seconds_bucket{le="0.25"} 2`) {
+		t.Errorf("le=0.25 bucket wrong, got:\n%s", out)
+	}
+	if !strings.Contains(out, `fetch_duration_seconds_bucket{le="0.5"} 3`) {
+		t.Errorf("le=0.5 bucket wrong, got:\n%s", out)
+	}
+	if !strings.Contains(out, `fetch_duration_seconds_bucket{le="1"} 4`) {
+		t.Errorf("le=1 bucket wrong, got:\n%s", out)
+	}
+	if !strings.Contains(out, `fetch_duration_seconds_bucket{le="+Inf"} 5`) {
+		t.Errorf("+Inf bucket wrong, got:\n%s", out)
+	}
+	if !strings.Contains(out, "fetch_duration_seconds_count 5") {
+		t.Errorf("count wrong, got:\n%s", out)
+	}
+}
+
+func TestMetricsRegistryObserveFetchError(t *testing.T) {
+	m := newMetricsRegistry()
+	city := City{State: "Texas", Name: "Austin"}
+
+	m.observeFetch(city, "openmeteo", Current{}, true, true, errUnexpectedStatus(503), 0)
+
+	var buf bytes.Buffer
+	m.writePrometheus(&buf)
+	if !strings.Contains(buf.String(), `capitals_weather_fetch_errors_total{state="Texas",provider="openmeteo"} 1`) {
+		t.Errorf("expected a fetch error counter for Texas/openmeteo, got:\n%s", buf.String())
+	}
+}
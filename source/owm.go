@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// owmResponse covers the subset of OpenWeatherMap's /data/2.5/weather
+// payload this tool cares about.
+type owmResponse struct {
+	Dt   int64 `json:"dt"`
+	Main struct {
+		Temp float64 `json:"temp"`
+	} `json:"main"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+		Deg   float64 `json:"deg"`
+	} `json:"wind"`
+	Weather []struct {
+		ID int `json:"id"`
+	} `json:"weather"`
+}
+
+// owmProvider fetches current conditions from OpenWeatherMap, using the
+// same app_id/units/lang parameters as the Telegraf openweathermap
+// input plugin.
+type owmProvider struct {
+	client     *http.Client
+	apiKey     string
+	lang       string
+	fahrenheit bool
+	mph        bool
+}
+
+func (p *owmProvider) Fetch(ctx context.Context, city City) (Current, error) {
+	units := "metric"
+	if p.fahrenheit {
+		units = "imperial"
+	}
+
+	url := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=%s",
+		city.Lat, city.Lon, p.apiKey, units)
+	if p.lang != "" {
+		url += "&lang=" + p.lang
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Current{}, err
+	}
+
+	resp, err := doWithRetry(ctx, p.client, city.State, req)
+	if err != nil {
+		return Current{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Current{}, fmt.Errorf("openweathermap: unexpected status %d", resp.StatusCode)
+	}
+
+	var om owmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&om); err != nil {
+		return Current{}, err
+	}
+	if len(om.Weather) == 0 {
+		return Current{}, errors.New("openweathermap: missing weather conditions in response")
+	}
+
+	return Current{
+		Time:          time.Unix(om.Dt, 0).UTC().Format(time.RFC3339),
+		Temperature:   om.Main.Temp,
+		WindSpeed:     convertOWMWindSpeed(om.Wind.Speed, p.fahrenheit, p.mph),
+		WindDirection: om.Wind.Deg,
+		WeatherCode:   om.Weather[0].ID,
+	}, nil
+}
+
+// convertOWMWindSpeed converts an OpenWeatherMap wind speed, reported in
+// the unit family tied to `units` (mph for imperial, m/s for metric),
+// into whatever the -mph flag actually asked for.
+func convertOWMWindSpeed(speed float64, fahrenheit, mph bool) float64 {
+	switch {
+	case fahrenheit && !mph: // have mph, want km/h
+		return speed * 1.60934
+	case !fahrenheit && mph: // have m/s, want mph
+		return speed * 2.23694
+	case !fahrenheit && !mph: // have m/s, want km/h
+		return speed * 3.6
+	default: // have mph, want mph
+		return speed
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestConvertOWMWindSpeed(t *testing.T) {
+	const raw = 10.0
+
+	tests := []struct {
+		name       string
+		fahrenheit bool
+		mph        bool
+		want       float64
+	}{
+		{"imperial, want mph: no conversion", true, true, raw},
+		{"metric, want km/h: m/s*3.6", false, false, raw * 3.6},
+		{"imperial, want km/h: mph*1.60934", true, false, raw * 1.60934},
+		{"metric, want mph: m/s*2.23694", false, true, raw * 2.23694},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertOWMWindSpeed(raw, tt.fahrenheit, tt.mph)
+			if diff := got - tt.want; diff > 1e-6 || diff < -1e-6 {
+				t.Fatalf("convertOWMWindSpeed(%v, %v, %v) = %v, want %v", raw, tt.fahrenheit, tt.mph, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+)
+
+// stringSliceFlag accumulates repeated occurrences of a flag, e.g.
+// "-city Boston,US -city Berlin,DE", into a slice.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// usStateCapitals is the built-in default location list, used when no
+// -city or -cities-file is given.
+func usStateCapitals() []City {
+	// Lat/Lon roughly for downtown/statehouse; good enough for demo purposes.
+	return []City{
+		{"Alabama", "Montgomery", 32.377716, -86.300568},
+		{"Alaska", "Juneau", 58.301598, -134.420212},
+		{"Arizona", "Phoenix", 33.448143, -112.096962},
+		{"Arkansas", "Little Rock", 34.746613, -92.288986},
+		{"California", "Sacramento", 38.576668, -121.493629},
+		{"Colorado", "Denver", 39.739227, -104.984856},
+		{"Connecticut", "Hartford", 41.764046, -72.682198},
+		{"Delaware", "Dover", 39.157307, -75.519722},
+		{"Florida", "Tallahassee", 30.438118, -84.281296},
+		{"Georgia", "Atlanta", 33.748997, -84.387985},
+		{"Hawaii", "Honolulu", 21.304850, -157.857758},
+		{"Idaho", "Boise", 43.615021, -116.202316},
+		{"Illinois", "Springfield", 39.798363, -89.654961},
+		{"Indiana", "Indianapolis", 39.768402, -86.158066},
+		{"Iowa", "Des Moines", 41.591087, -93.603729},
+		{"Kansas", "Topeka", 39.047345, -95.675157},
+		{"Kentucky", "Frankfort", 38.186722, -84.875374},
+		{"Louisiana", "Baton Rouge", 30.457069, -91.187393},
+		{"Maine", "Augusta", 44.307167, -69.781693},
+		{"Maryland", "Annapolis", 38.978764, -76.490936},
+		{"Massachusetts", "Boston", 42.358162, -71.063698},
+		{"Michigan", "Lansing", 42.733635, -84.555328},
+		{"Minnesota", "Saint Paul", 44.955097, -93.102211},
+		{"Mississippi", "Jackson", 32.303848, -90.182106},
+		{"Missouri", "Jefferson City", 38.579201, -92.172935},
+		{"Montana", "Helena", 46.585709, -112.018417},
+		{"Nebraska", "Lincoln", 40.808075, -96.699654},
+		{"Nevada", "Carson City", 39.163914, -119.766121},
+		{"New Hampshire", "Concord", 43.206898, -71.537994},
+		{"New Jersey", "Trenton", 40.220596, -74.769913},
+		{"New Mexico", "Santa Fe", 35.682240, -105.939728},
+		{"New York", "Albany", 42.652843, -73.757874},
+		{"North Carolina", "Raleigh", 35.780430, -78.639099},
+		{"North Dakota", "Bismarck", 46.820850, -100.783318},
+		{"Ohio", "Columbus", 39.961346, -82.999069},
+		{"Oklahoma", "Oklahoma City", 35.492207, -97.503342},
+		{"Oregon", "Salem", 44.938461, -123.030403},
+		{"Pennsylvania", "Harrisburg", 40.264378, -76.883598},
+		{"Rhode Island", "Providence", 41.830914, -71.414963},
+		{"South Carolina", "Columbia", 34.000343, -81.033211},
+		{"South Dakota", "Pierre", 44.367031, -100.346405},
+		{"Tennessee", "Nashville", 36.165810, -86.784241},
+		{"Texas", "Austin", 30.274670, -97.740349},
+		{"Utah", "Salt Lake City", 40.777477, -111.888237},
+		{"Vermont", "Montpelier", 44.262436, -72.580536},
+		{"Virginia", "Richmond", 37.538857, -77.433640},
+		{"Washington", "Olympia", 47.035805, -122.905014},
+		{"West Virginia", "Charleston", 38.336246, -81.612328},
+		{"Wisconsin", "Madison", 43.074684, -89.384445},
+		{"Wyoming", "Cheyenne", 41.140259, -104.820236},
+	}
+}
+
+// countryAbbreviations expands common country abbreviations so a user
+// can type "Boston, US" or "London, UK" and have it match Open-Meteo's
+// geocoding results, which report full country names. Two-letter codes
+// not listed here (e.g. "DE") are left as is and matched against the
+// result's country code instead.
+var countryAbbreviations = map[string]string{
+	"US":  "United States",
+	"USA": "United States",
+	"UK":  "United Kingdom",
+}
+
+// expandRegion expands region into a full country name if it's a known
+// abbreviation, otherwise returns it unchanged so it can still be
+// matched against an admin1 (state/province) name.
+func expandRegion(region string) string {
+	if full, ok := countryAbbreviations[strings.ToUpper(region)]; ok {
+		return full
+	}
+	return region
+}
+
+// geocodeResult is one match from Open-Meteo's geocoding API.
+type geocodeResult struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code"`
+	Admin1      string  `json:"admin1"`
+}
+
+type geocodeResponse struct {
+	Results []geocodeResult `json:"results"`
+}
+
+// geocodeSearch queries Open-Meteo's geocoding API for name.
+func geocodeSearch(ctx context.Context, client *http.Client, name string) ([]geocodeResult, error) {
+	url := "https://geocoding-api.open-meteo.com/v1/search?name=" + neturl.QueryEscape(name) + "&count=10&language=en&format=json"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := doWithRetry(ctx, client, "geocoding", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding: unexpected status %d", resp.StatusCode)
+	}
+
+	var gr geocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return nil, err
+	}
+	return gr.Results, nil
+}
+
+// parseCityQuery splits a "City, Region" query into its parts. Region is
+// empty if the query didn't include a comma.
+func parseCityQuery(raw string) (name, region string) {
+	parts := strings.SplitN(raw, ",", 2)
+	name = strings.TrimSpace(parts[0])
+	if len(parts) == 2 {
+		region = strings.TrimSpace(parts[1])
+	}
+	return name, region
+}
+
+// matchesRegion reports whether a geocode result belongs to the given
+// region, matching against admin1 (state/province), country, or country
+// code, after expanding abbreviations like "US" or "UK".
+func matchesRegion(r geocodeResult, region string) bool {
+	if region == "" {
+		return true
+	}
+	region = expandRegion(region)
+	return strings.EqualFold(r.Admin1, region) ||
+		strings.EqualFold(r.Country, region) ||
+		strings.EqualFold(r.CountryCode, region)
+}
+
+// resolvePlace resolves a "-city" query to a City, consulting cache
+// first and persisting any newly resolved location back to it.
+func resolvePlace(ctx context.Context, client *http.Client, cache *GeocodeCache, raw string) (City, error) {
+	if city, ok := cache.Get(raw); ok {
+		return city, nil
+	}
+
+	name, region := parseCityQuery(raw)
+	results, err := geocodeSearch(ctx, client, name)
+	if err != nil {
+		return City{}, err
+	}
+
+	for _, r := range results {
+		if !matchesRegion(r, region) {
+			continue
+		}
+		city := City{State: r.Admin1, Name: r.Name, Lat: r.Latitude, Lon: r.Longitude}
+		if city.State == "" {
+			city.State = r.Country
+		}
+		if err := cache.Set(raw, city); err != nil {
+			slog.Warn("saving geocode cache", "error", err)
+		}
+		return city, nil
+	}
+	return City{}, fmt.Errorf("no geocoding match for %q", raw)
+}
+
+// resolvePlaces resolves every query in raws, in order, logging and
+// skipping any that fail to resolve.
+func resolvePlaces(ctx context.Context, client *http.Client, cache *GeocodeCache, raws []string) []City {
+	var cities []City
+	for _, raw := range raws {
+		city, err := resolvePlace(ctx, client, cache, raw)
+		if err != nil {
+			slog.Error("resolving city", "query", raw, "error", err)
+			continue
+		}
+		cities = append(cities, city)
+	}
+	return cities
+}
+
+// readCitiesFile reads newline-separated city queries from path,
+// skipping blank lines and lines starting with "#".
+func readCitiesFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		queries = append(queries, line)
+	}
+	return queries, nil
+}
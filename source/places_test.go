@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestExpandRegion(t *testing.T) {
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{"US", "United States"},
+		{"USA", "United States"},
+		{"UK", "United Kingdom"},
+		{"us", "United States"},
+		{"Texas", "Texas"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := expandRegion(tt.region); got != tt.want {
+			t.Errorf("expandRegion(%q) = %q, want %q", tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesRegion(t *testing.T) {
+	boston := geocodeResult{Name: "Boston", Admin1: "Massachusetts", Country: "United States", CountryCode: "US"}
+	berlin := geocodeResult{Name: "Berlin", Admin1: "Berlin", Country: "Germany", CountryCode: "DE"}
+
+	tests := []struct {
+		name   string
+		result geocodeResult
+		region string
+		want   bool
+	}{
+		{"empty region matches anything", boston, "", true},
+		{"matches admin1 exactly", boston, "Massachusetts", true},
+		{"matches country abbreviation", boston, "US", true},
+		{"matches country abbreviation case-insensitively", boston, "us", true},
+		{"matches full country name", berlin, "Germany", true},
+		{"matches country code directly", berlin, "DE", true},
+		{"rejects mismatched region", boston, "California", false},
+		{"rejects mismatched country", berlin, "US", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesRegion(tt.result, tt.region); got != tt.want {
+				t.Errorf("matchesRegion(%+v, %q) = %v, want %v", tt.result, tt.region, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCityQuery(t *testing.T) {
+	tests := []struct {
+		raw        string
+		wantName   string
+		wantRegion string
+	}{
+		{"Boston, US", "Boston", "US"},
+		{"Berlin,DE", "Berlin", "DE"},
+		{"Tokyo", "Tokyo", ""},
+		{"  Paris , France ", "Paris", "France"},
+	}
+
+	for _, tt := range tests {
+		name, region := parseCityQuery(tt.raw)
+		if name != tt.wantName || region != tt.wantRegion {
+			t.Errorf("parseCityQuery(%q) = (%q, %q), want (%q, %q)", tt.raw, name, region, tt.wantName, tt.wantRegion)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Provider fetches current conditions for a city from some weather
+// backend. Implementations should respect ctx cancellation/deadlines.
+type Provider interface {
+	Fetch(ctx context.Context, city City) (Current, error)
+}
+
+// newProvider builds the Provider named by name. "auto" races Open-Meteo,
+// wttr.in and, only when apiKey is set, OpenWeatherMap, and returns the
+// first one to succeed per city.
+func newProvider(name string, client *http.Client, apiKey, lang string, fahrenheit, mph bool) (Provider, error) {
+	switch name {
+	case "openmeteo", "":
+		return &openMeteoProvider{client: client, fahrenheit: fahrenheit, mph: mph}, nil
+	case "openweathermap", "owm":
+		if apiKey == "" {
+			return nil, fmt.Errorf("provider %q requires -api-key", name)
+		}
+		return &owmProvider{client: client, apiKey: apiKey, lang: lang, fahrenheit: fahrenheit, mph: mph}, nil
+	case "wttr":
+		return &wttrProvider{client: client, fahrenheit: fahrenheit, mph: mph}, nil
+	case "auto":
+		var providers []Provider
+		providers = append(providers, &openMeteoProvider{client: client, fahrenheit: fahrenheit, mph: mph})
+		if apiKey != "" {
+			providers = append(providers, &owmProvider{client: client, apiKey: apiKey, lang: lang, fahrenheit: fahrenheit, mph: mph})
+		}
+		providers = append(providers, &wttrProvider{client: client, fahrenheit: fahrenheit, mph: mph})
+		return &autoProvider{providers: providers}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+}
+
+// openMeteoProvider is the original Open-Meteo backend.
+type openMeteoProvider struct {
+	client     *http.Client
+	fahrenheit bool
+	mph        bool
+}
+
+func (p *openMeteoProvider) Fetch(ctx context.Context, city City) (Current, error) {
+	return fetchCurrent(ctx, p.client, city, p.fahrenheit, p.mph)
+}
+
+// autoProvider races its providers and returns the first successful
+// response, cancelling the rest.
+type autoProvider struct {
+	providers []Provider
+}
+
+func (p *autoProvider) Fetch(ctx context.Context, city City) (Current, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type attempt struct {
+		cur Current
+		err error
+	}
+	results := make(chan attempt, len(p.providers))
+	for _, prov := range p.providers {
+		go func(prov Provider) {
+			cur, err := prov.Fetch(ctx, city)
+			results <- attempt{cur: cur, err: err}
+		}(prov)
+	}
+
+	var lastErr error
+	for range p.providers {
+		a := <-results
+		if a.err == nil {
+			return a.cur, nil
+		}
+		lastErr = a.err
+	}
+	return Current{}, fmt.Errorf("all providers failed for %s: %w", city.Name, lastErr)
+}
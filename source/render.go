@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// sparkTicks are the block characters used to render a compact sparkline,
+// lowest to highest.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders vals as a single-line bar chart using block
+// characters, scaled between the series' own min and max.
+func sparkline(vals []float64) string {
+	if len(vals) == 0 {
+		return ""
+	}
+	min, max := vals[0], vals[0]
+	for _, v := range vals {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	span := max - min
+	var b strings.Builder
+	for _, v := range vals {
+		idx := len(sparkTicks) - 1
+		if span > 0 {
+			idx = int((v - min) / span * float64(len(sparkTicks)-1))
+		}
+		b.WriteRune(sparkTicks[idx])
+	}
+	return b.String()
+}
+
+// precipBar renders precipitation probabilities (0-100) as a sparkline
+// over the same tick set, so it matches the temperature sparkline in the
+// column beside it. Like sparkline, it scales between the series' own
+// min and max, not between 0 and 100.
+func precipBar(vals []float64) string {
+	return sparkline(vals)
+}
+
+// jsonResult mirrors Result for JSON/ndjson output: Result.Err is an
+// error interface, which encoding/json marshals as an empty object, so
+// it's shadowed here with the rendered error message.
+type jsonResult struct {
+	City City
+	Cur  Current
+	Err  string `json:"Err,omitempty"`
+}
+
+func toJSONResults(out []Result) []jsonResult {
+	jr := make([]jsonResult, len(out))
+	for i, r := range out {
+		jr[i] = jsonResult{City: r.City, Cur: r.Cur}
+		if r.Err != nil {
+			jr[i].Err = r.Err.Error()
+		}
+	}
+	return jr
+}
+
+// renderCurrent prints current-conditions results in the requested format.
+func renderCurrent(out []Result, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(toJSONResults(out))
+	case "ndjson":
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range toJSONResults(out) {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		return renderCurrentCSV(out)
+	default:
+		renderCurrentTable(out)
+		return nil
+	}
+}
+
+func renderCurrentCSV(out []Result) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if err := w.Write([]string{"state", "capital", "temperature", "wind_speed", "wind_direction", "time", "error"}); err != nil {
+		return err
+	}
+	for _, r := range out {
+		errStr := ""
+		if r.Err != nil {
+			errStr = r.Err.Error()
+		}
+		if err := w.Write([]string{
+			r.City.State, r.City.Name,
+			strconv.FormatFloat(r.Cur.Temperature, 'f', 1, 64),
+			strconv.FormatFloat(r.Cur.WindSpeed, 'f', 1, 64),
+			windDir(r.Cur.WindDirection),
+			r.Cur.Time, errStr,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderCurrentTable(out []Result) {
+	fmt.Printf("%s\n", strings.Repeat("-", 86))
+	fmt.Printf("%-15s | %-18s | %9s | %7s | %3s | %s\n", "STATE", "CAPITAL", "TEMP", "WIND", "DIR", "AT")
+	fmt.Printf("%s\n", strings.Repeat("-", 86))
+	for _, r := range out {
+		if r.Err != nil {
+			fmt.Printf("%-15s | %-18s | %9s | %7s | %3s | %v\n", r.City.State, r.City.Name, "ERR", "-", "-", r.Err)
+			continue
+		}
+		dir := windDir(r.Cur.WindDirection)
+		fmt.Printf("%-15s | %-18s | %6.1f° | %5.1f | %3s | %s\n",
+			r.City.State, r.City.Name, r.Cur.Temperature, r.Cur.WindSpeed, dir, r.Cur.Time)
+	}
+	fmt.Printf("%s\n", strings.Repeat("-", 86))
+}
+
+// renderForecasts prints forecast results in the requested format.
+func renderForecasts(out []Forecast, mode, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case "ndjson":
+		enc := json.NewEncoder(os.Stdout)
+		for _, f := range out {
+			if err := enc.Encode(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		return renderForecastCSV(out, mode)
+	default:
+		renderForecastTable(out, mode)
+		return nil
+	}
+}
+
+func renderForecastCSV(out []Forecast, mode string) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if mode != "daily" {
+		if err := w.Write([]string{"state", "capital", "time", "temperature", "precip_probability"}); err != nil {
+			return err
+		}
+		for _, f := range out {
+			for _, h := range f.Hourly {
+				if err := w.Write([]string{
+					f.City.State, f.City.Name, h.Time,
+					strconv.FormatFloat(h.Temperature, 'f', 1, 64),
+					strconv.FormatFloat(h.PrecipProbability, 'f', 0, 64),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := w.Write([]string{"state", "capital", "date", "temp_max", "temp_min", "sunrise", "sunset"}); err != nil {
+		return err
+	}
+	for _, f := range out {
+		for _, d := range f.Daily {
+			if err := w.Write([]string{
+				f.City.State, f.City.Name, d.Date,
+				strconv.FormatFloat(d.TempMax, 'f', 1, 64),
+				strconv.FormatFloat(d.TempMin, 'f', 1, 64),
+				d.Sunrise, d.Sunset,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func renderForecastTable(out []Forecast, mode string) {
+	fmt.Printf("%s\n", strings.Repeat("-", 100))
+	fmt.Printf("%-15s | %-18s | %-20s | %-20s | %s\n", "STATE", "CAPITAL", "TEMP", "PRECIP %", "SUN")
+	fmt.Printf("%s\n", strings.Repeat("-", 100))
+	for _, f := range out {
+		temps := make([]float64, len(f.Hourly))
+		precip := make([]float64, len(f.Hourly))
+		for i, h := range f.Hourly {
+			temps[i] = h.Temperature
+			precip[i] = h.PrecipProbability
+		}
+
+		var sun string
+		if len(f.Daily) > 0 {
+			sun = fmt.Sprintf("%s-%s", f.Daily[0].Sunrise, f.Daily[0].Sunset)
+		}
+
+		tempSpark, precipSpark := "-", "-"
+		if mode != "daily" {
+			tempSpark = sparkline(temps)
+			precipSpark = precipBar(precip)
+		}
+		fmt.Printf("%-15s | %-18s | %-20s | %-20s | %s\n", f.City.State, f.City.Name, tempSpark, precipSpark, sun)
+	}
+	fmt.Printf("%s\n", strings.Repeat("-", 100))
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestSparklineEmpty(t *testing.T) {
+	if got := sparkline(nil); got != "" {
+		t.Fatalf("sparkline(nil) = %q, want empty string", got)
+	}
+}
+
+func TestSparklineFlatSeriesUsesTopTick(t *testing.T) {
+	got := sparkline([]float64{5, 5, 5})
+	want := string(sparkTicks[len(sparkTicks)-1])
+	for _, r := range got {
+		if string(r) != want {
+			t.Fatalf("sparkline of a flat series = %q, want every tick to be %q", got, want)
+		}
+	}
+	if len([]rune(got)) != 3 {
+		t.Fatalf("sparkline length = %d, want 3", len([]rune(got)))
+	}
+}
+
+func TestSparklineEndpointsUseMinMaxTicks(t *testing.T) {
+	runes := []rune(sparkline([]float64{0, 50, 100}))
+	if len(runes) != 3 {
+		t.Fatalf("got %d ticks, want 3", len(runes))
+	}
+	if runes[0] != sparkTicks[0] {
+		t.Errorf("lowest value tick = %q, want %q", runes[0], sparkTicks[0])
+	}
+	if runes[2] != sparkTicks[len(sparkTicks)-1] {
+		t.Errorf("highest value tick = %q, want %q", runes[2], sparkTicks[len(sparkTicks)-1])
+	}
+}
+
+func TestRenderCurrentPreservesErrorInJSON(t *testing.T) {
+	out := toJSONResults([]Result{
+		{City: City{State: "Texas", Name: "Austin"}, Err: errUnexpectedStatus(503)},
+	})
+	if out[0].Err == "" {
+		t.Fatalf("expected non-empty error message in jsonResult, got empty string")
+	}
+}
+
+// errUnexpectedStatus is a tiny error stand-in so this file doesn't need
+// to import fmt just to build a non-nil error for the test above.
+type errUnexpectedStatus int
+
+func (e errUnexpectedStatus) Error() string {
+	return "unexpected status"
+}
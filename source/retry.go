@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	retryBaseDelay   = 250 * time.Millisecond
+	retryMaxDelay    = 4 * time.Second
+	retryMaxAttempts = 4
+)
+
+// NewRateLimiter returns a limiter allowing perSecond requests/second,
+// shared across goroutines and decoupled from whatever concurrency limit
+// (semaphore) callers use. A non-positive perSecond disables pacing.
+func NewRateLimiter(perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), 1)
+}
+
+// sharedLimiter paces every outgoing request in the process, regardless
+// of which provider or goroutine issues it. main wires it up from -rate.
+var sharedLimiter = NewRateLimiter(10)
+
+// doWithRetry executes req, retrying transport errors and 429/503
+// responses for up to retryMaxAttempts attempts in all, with exponential
+// backoff and jitter (see backoffDelay), honoring Retry-After and the parent context's
+// deadline. Each attempt is logged with state, attempt, status, duration,
+// and error so failures are diagnosable in production runs.
+func doWithRetry(ctx context.Context, client *http.Client, state string, req *http.Request) (*http.Response, error) {
+	var lastErr error
+	for attempt := 1; attempt <= retryMaxAttempts; attempt++ {
+		if err := sharedLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req.Clone(ctx))
+		duration := time.Since(start)
+
+		if err != nil {
+			lastErr = err
+			slog.Warn("fetch attempt failed", "state", state, "attempt", attempt, "duration_ms", duration.Milliseconds(), "error", err)
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			if attempt < retryMaxAttempts {
+				if err := sleepFor(ctx, backoffDelay(attempt)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			slog.Warn("fetch attempt rate limited", "state", state, "attempt", attempt, "status", resp.StatusCode, "duration_ms", duration.Milliseconds())
+			if attempt == retryMaxAttempts {
+				break
+			}
+			delay := retryAfter
+			if delay <= 0 {
+				delay = backoffDelay(attempt)
+			}
+			if err := sleepFor(ctx, delay); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		slog.Info("fetch attempt succeeded", "state", state, "attempt", attempt, "status", resp.StatusCode, "duration_ms", duration.Milliseconds())
+		return resp, nil
+	}
+	return nil, fmt.Errorf("giving up after %d attempts: %w", retryMaxAttempts, lastErr)
+}
+
+// backoffDelay returns the exponential backoff (with jitter) before the
+// next attempt: retryBaseDelay doubled per attempt and capped at
+// retryMaxDelay, then jittered to between half and all of that.
+func backoffDelay(attempt int) time.Duration {
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt-1))
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// sleepFor blocks for d or until ctx is done, whichever comes first.
+func sleepFor(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which may be either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelayCapped(t *testing.T) {
+	for attempt := 1; attempt <= retryMaxAttempts+2; attempt++ {
+		d := backoffDelay(attempt)
+		if d > retryMaxDelay {
+			t.Errorf("backoffDelay(%d) = %v, want <= %v", attempt, d, retryMaxDelay)
+		}
+		if d <= 0 {
+			t.Errorf("backoffDelay(%d) = %v, want > 0", attempt, d)
+		}
+	}
+}
+
+func TestBackoffDelayGrowsWithAttempt(t *testing.T) {
+	// Each attempt's minimum possible delay (no jitter) should not be
+	// lower than the previous attempt's maximum, until the cap kicks in.
+	prevMax := time.Duration(0)
+	for attempt := 1; attempt <= 3; attempt++ {
+		uncapped := retryBaseDelay * time.Duration(1<<uint(attempt-1))
+		if uncapped > retryMaxDelay {
+			break
+		}
+		if uncapped/2 < prevMax {
+			t.Errorf("attempt %d delay did not grow from previous attempt", attempt)
+		}
+		prevMax = uncapped
+	}
+}
+
+func TestParseRetryAfterEmpty(t *testing.T) {
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+}
+
+func TestParseRetryAfterSeconds(t *testing.T) {
+	if got := parseRetryAfter("5"); got != 5*time.Second {
+		t.Errorf("parseRetryAfter(\"5\") = %v, want 5s", got)
+	}
+}
+
+func TestParseRetryAfterHTTPDate(t *testing.T) {
+	future := time.Now().Add(10 * time.Second).UTC()
+	got := parseRetryAfter(future.Format(http.TimeFormat))
+	if got <= 0 || got > 11*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, want ~10s", future.Format(http.TimeFormat), got)
+	}
+}
+
+func TestParseRetryAfterInvalid(t *testing.T) {
+	if got := parseRetryAfter("not-a-valid-value"); got != 0 {
+		t.Errorf("parseRetryAfter(invalid) = %v, want 0", got)
+	}
+}
+
+func TestDoWithRetryHonorsRetryAfterThenSucceeds(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	resp, err := doWithRetry(context.Background(), srv.Client(), "TS", req)
+	if err != nil {
+		t.Fatalf("doWithRetry returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("resp.StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 (one 429, then one 200)", calls)
+	}
+}
+
+func TestDoWithRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	resp, err := doWithRetry(context.Background(), srv.Client(), "TS", req)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("doWithRetry returned nil error, want error after exhausting attempts")
+	}
+	if calls != retryMaxAttempts {
+		t.Errorf("calls = %d, want %d", calls, retryMaxAttempts)
+	}
+}
+
+func TestDoWithRetryRespectsContextDeadline(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	resp, err := doWithRetry(ctx, srv.Client(), "TS", req)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("doWithRetry returned nil error, want context deadline error")
+	}
+	if calls == 0 {
+		t.Error("calls = 0, want at least one attempt before the deadline hit")
+	}
+	if calls >= retryMaxAttempts {
+		t.Errorf("calls = %d, want fewer than %d (deadline should cut the loop short)", calls, retryMaxAttempts)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// server holds the state backing -serve mode: the most recent result per
+// capital, plus the metrics scraped from fetching them.
+type server struct {
+	mu       sync.RWMutex
+	latest   map[string]Result // keyed by City.State
+	provider Provider
+	provName string
+	capitals []City
+	metrics  *metricsRegistry
+
+	concurrency int
+	fahrenheit  bool
+	mph         bool
+}
+
+// runServer starts the background scraper loop and serves /capitals,
+// /capitals/{state}, and /metrics on addr until ctx is cancelled or the
+// process receives SIGINT/SIGTERM, then shuts down gracefully.
+func runServer(ctx context.Context, addr string, capitals []City, provider Provider, provName string, concurrency int, fahrenheit, mph bool, scrapeInterval time.Duration) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	s := &server{
+		latest:      make(map[string]Result),
+		provider:    provider,
+		provName:    provName,
+		capitals:    capitals,
+		metrics:     newMetricsRegistry(),
+		concurrency: concurrency,
+		fahrenheit:  fahrenheit,
+		mph:         mph,
+	}
+
+	go s.scrapeLoop(ctx, scrapeInterval)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/capitals", s.handleCapitals)
+	mux.HandleFunc("/capitals/", s.handleCapital)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	httpServer := &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("serving", "addr", addr)
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+		slog.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+// scrapeLoop refreshes every capital on scrapeInterval, bounded by the
+// same semaphore-based concurrency limit the one-shot CLI mode uses.
+func (s *server) scrapeLoop(ctx context.Context, scrapeInterval time.Duration) {
+	s.scrapeAll(ctx)
+
+	ticker := time.NewTicker(scrapeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.scrapeAll(ctx)
+		}
+	}
+}
+
+func (s *server) scrapeAll(ctx context.Context) {
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for _, city := range s.capitals {
+		wg.Add(1)
+		go func(city City) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			cur, err := s.provider.Fetch(ctx, city)
+			s.metrics.observeFetch(city, s.provName, cur, s.fahrenheit, s.mph, err, time.Since(start))
+
+			s.mu.Lock()
+			s.latest[city.State] = Result{City: city, Cur: cur, Err: err}
+			s.mu.Unlock()
+		}(city)
+	}
+	wg.Wait()
+}
+
+func (s *server) handleCapitals(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	out := make([]Result, 0, len(s.latest))
+	for _, res := range s.latest {
+		out = append(out, res)
+	}
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toJSONResults(out))
+}
+
+func (s *server) handleCapital(w http.ResponseWriter, r *http.Request) {
+	state := strings.TrimPrefix(r.URL.Path, "/capitals/")
+	if state == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	res, ok := s.latest[state]
+	s.mu.RUnlock()
+	if !ok {
+		http.Error(w, "unknown state", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toJSONResults([]Result{res})[0])
+}
+
+func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.metrics.writePrometheus(w)
+}
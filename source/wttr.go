@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// wttrCondition is the subset of wttr.in's `?format=j1` current_condition
+// entry this tool needs.
+type wttrCondition struct {
+	TempC            string `json:"temp_C"`
+	TempF            string `json:"temp_F"`
+	WindspeedKmph    string `json:"windspeedKmph"`
+	WindspeedMiles   string `json:"windspeedMiles"`
+	Winddir16Point   string `json:"winddir16Point"`
+	WeatherCode      string `json:"weatherCode"`
+	LocalObsDateTime string `json:"localObsDateTime"`
+}
+
+type wttrResponse struct {
+	CurrentCondition []wttrCondition `json:"current_condition"`
+}
+
+// wttrProvider fetches current conditions from wttr.in, which needs no
+// API key and is used here as a keyless fallback.
+type wttrProvider struct {
+	client     *http.Client
+	fahrenheit bool
+	mph        bool
+}
+
+func (p *wttrProvider) Fetch(ctx context.Context, city City) (Current, error) {
+	url := fmt.Sprintf("https://wttr.in/%f,%f?format=j1", city.Lat, city.Lon)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Current{}, err
+	}
+
+	resp, err := doWithRetry(ctx, p.client, city.State, req)
+	if err != nil {
+		return Current{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Current{}, fmt.Errorf("wttr.in: unexpected status %d", resp.StatusCode)
+	}
+
+	var w wttrResponse
+	if err := json.NewDecoder(resp.Body).Decode(&w); err != nil {
+		return Current{}, err
+	}
+	if len(w.CurrentCondition) == 0 {
+		return Current{}, errors.New("wttr.in: missing current_condition in response")
+	}
+	cond := w.CurrentCondition[0]
+
+	temp := cond.TempC
+	if p.fahrenheit {
+		temp = cond.TempF
+	}
+	tempVal, err := strconv.ParseFloat(temp, 64)
+	if err != nil {
+		return Current{}, fmt.Errorf("wttr.in: parsing temperature: %w", err)
+	}
+
+	wind := cond.WindspeedKmph
+	if p.mph {
+		wind = cond.WindspeedMiles
+	}
+	windVal, err := strconv.ParseFloat(wind, 64)
+	if err != nil {
+		return Current{}, fmt.Errorf("wttr.in: parsing wind speed: %w", err)
+	}
+
+	code, _ := strconv.Atoi(cond.WeatherCode)
+
+	return Current{
+		Time:          cond.LocalObsDateTime,
+		Temperature:   tempVal,
+		WindSpeed:     windVal,
+		WindDirection: windDirToDegrees(cond.Winddir16Point),
+		WeatherCode:   code,
+	}, nil
+}
+
+// windDirToDegrees converts a 16-point compass label (as reported by
+// wttr.in) back to degrees, mirroring windDir's own table.
+func windDirToDegrees(point string) float64 {
+	dirs := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	for i, d := range dirs {
+		if d == point {
+			return float64(i) * 22.5
+		}
+	}
+	return 0
+}